@@ -0,0 +1,100 @@
+// Package bridge establishes a persistent duplex message channel between Go
+// and JS using MessageChannel/postMessage, instead of the one-shot function
+// calls js.Global().Set wires up elsewhere. It suits firmware telemetry or
+// DFU byte streams, where request/response correlation, backpressure, and
+// long-lived streams matter more than a single call/return.
+package bridge
+
+import "syscall/js"
+
+// chanBufferSize bounds how many unread messages queue up on either side
+// before a slow consumer applies backpressure to the sender.
+const chanBufferSize = 64
+
+// Message is a single framed message exchanged over the bridge. Payload
+// carries binary data (e.g. a firmware chunk); Type distinguishes message
+// kinds for the receiver's dispatch logic.
+type Message struct {
+	ID      string
+	Type    string
+	Payload []byte
+}
+
+// Port is the Go side of one end of a MessageChannel, returned by Open.
+// Callers must call Close when done with it to release the onmessage
+// js.Func, stop the writer goroutine draining its out channel, and unblock
+// any reader ranging over its in channel.
+type Port struct {
+	value js.Value
+	onmsg js.Func
+	done  chan struct{}
+	inCh  chan Message
+}
+
+// Close closes the underlying MessagePort (so no further messages can
+// arrive), releases the onmessage js.Func, stops the goroutine that was
+// forwarding writes to JS, and closes in so any `range in` reader returns
+// instead of blocking forever.
+func (p *Port) Close() {
+	p.value.Call("close")
+	p.onmsg.Release()
+	close(p.done)
+	close(p.inCh)
+}
+
+// Open creates a MessageChannel, wires up port1 for Go-side receive/send,
+// and returns a Port (which must be Close()d when done), the Go-side read
+// and write channels, and port2 — the js.Value to transfer to a worker or
+// the page. A goroutine started here posts each message sent to out on
+// port1 until Close is called.
+func Open() (port *Port, in <-chan Message, out chan<- Message, jsPort js.Value) {
+	channel := js.Global().Get("MessageChannel").New()
+	port1 := channel.Get("port1")
+	port2 := channel.Get("port2")
+
+	inCh := make(chan Message, chanBufferSize)
+	outCh := make(chan Message, chanBufferSize)
+
+	p := &Port{value: port1, done: make(chan struct{}), inCh: inCh}
+	p.onmsg = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		data := args[0].Get("data")
+		inCh <- Message{
+			ID:      data.Get("id").String(),
+			Type:    data.Get("type").String(),
+			Payload: toBytes(data.Get("payload")),
+		}
+		return nil
+	})
+	port1.Set("onmessage", p.onmsg)
+	port1.Call("start")
+
+	go func() {
+		for {
+			select {
+			case msg := <-outCh:
+				payload := js.Global().Get("Uint8Array").New(len(msg.Payload))
+				js.CopyBytesToJS(payload, msg.Payload)
+				port1.Call("postMessage", js.ValueOf(map[string]interface{}{
+					"id":      msg.ID,
+					"type":    msg.Type,
+					"payload": payload,
+				}))
+			case <-p.done:
+				return
+			}
+		}
+	}()
+
+	return p, inCh, outCh, port2
+}
+
+// toBytes copies a JS Uint8Array payload into a Go byte slice. An
+// undefined/null payload (messages without binary data) yields nil.
+func toBytes(v js.Value) []byte {
+	if v.Type() != js.TypeObject {
+		return nil
+	}
+	b := make([]byte, v.Get("length").Int())
+	js.CopyBytesToGo(b, v)
+	return b
+}
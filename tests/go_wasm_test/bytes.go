@@ -0,0 +1,37 @@
+package main
+
+import "syscall/js"
+
+// ToJSBytes copies b into a new JS Uint8Array with a single typed-array copy,
+// the fast path for handing large buffers (firmware images, packet captures)
+// to JS — orders of magnitude faster than passing them as ints or JSON strings.
+func ToJSBytes(b []byte) js.Value {
+	arr := js.Global().Get("Uint8Array").New(len(b))
+	js.CopyBytesToJS(arr, b)
+	return arr
+}
+
+// FromJSBytes copies a JS Uint8Array (or Uint8ClampedArray) into a new Go
+// byte slice. Any other value, including other typed arrays, makes
+// js.CopyBytesToGo panic.
+func FromJSBytes(v js.Value) []byte {
+	b := make([]byte, v.Get("length").Int())
+	js.CopyBytesToGo(b, v)
+	return b
+}
+
+// RegisterBinary exposes fn under name on js.Global() as a direct
+// []byte -> []byte call using ToJSBytes/FromJSBytes, bypassing Register's
+// reflection overhead for the hot path of moving firmware payloads.
+func RegisterBinary(name string, fn func([]byte) ([]byte, error)) {
+	js.Global().Set(name, js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) != 1 {
+			return jsError(errMissingArgs)
+		}
+		result, err := fn(FromJSBytes(args[0]))
+		if err != nil {
+			return jsError(err)
+		}
+		return ToJSBytes(result)
+	}))
+}
@@ -0,0 +1,140 @@
+// Package dom wraps syscall/js with typed helpers for the browser DOM, so
+// callers can drive a page (e.g. render nRF5340 device state) without
+// sprinkling raw js.Value calls through application code.
+package dom
+
+import "syscall/js"
+
+// Document returns the global document object.
+func Document() js.Value {
+	return js.Global().Get("document")
+}
+
+// Element wraps a DOM element js.Value with typed helpers.
+type Element struct {
+	Value js.Value
+}
+
+// GetElementByID looks up an element by its id attribute. If no element was
+// found, the returned Element wraps JS null — callers should check Found()
+// before using the other methods, which no-op rather than panic when Found
+// is false.
+func GetElementByID(id string) *Element {
+	return &Element{Value: Document().Call("getElementById", id)}
+}
+
+// Found reports whether the element actually resolved to a DOM node, as
+// opposed to wrapping JS null (e.g. from a GetElementByID miss).
+func (e *Element) Found() bool {
+	return e.Value.Truthy()
+}
+
+// SetStyle sets a single inline CSS property on the element. It is a no-op
+// if the element was not Found.
+func (e *Element) SetStyle(key, value string) {
+	if !e.Found() {
+		return
+	}
+	e.Value.Get("style").Set(key, value)
+}
+
+// SetAttribute sets an HTML attribute on the element. It is a no-op if the
+// element was not Found.
+func (e *Element) SetAttribute(name, value string) {
+	if !e.Found() {
+		return
+	}
+	e.Value.Call("setAttribute", name, value)
+}
+
+// SetText sets the element's textContent. It is a no-op if the element was
+// not Found.
+func (e *Element) SetText(text string) {
+	if !e.Found() {
+		return
+	}
+	e.Value.Set("textContent", text)
+}
+
+// Event wraps a DOM event js.Value.
+type Event struct {
+	Value js.Value
+}
+
+// PreventDefault calls preventDefault() on the underlying event.
+func (e Event) PreventDefault() {
+	e.Value.Call("preventDefault")
+}
+
+// Handler is a registered event listener that must be Close()d to release
+// its underlying js.Func and avoid leaking it for the page's lifetime.
+type Handler struct {
+	target js.Value
+	event  string
+	fn     js.Func
+}
+
+// Close removes the listener and releases its js.Func.
+func (h *Handler) Close() {
+	h.target.Call("removeEventListener", h.event, h.fn)
+	h.fn.Release()
+}
+
+// AddEventListener registers handler for event on the element and returns a
+// Handler the caller must Close() when done. It returns nil if the element
+// was not Found, since there is nothing to listen on.
+func (e *Element) AddEventListener(event string, handler func(Event)) *Handler {
+	if !e.Found() {
+		return nil
+	}
+
+	fn := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		handler(Event{Value: args[0]})
+		return nil
+	})
+	e.Value.Call("addEventListener", event, fn)
+	return &Handler{target: e.Value, event: event, fn: fn}
+}
+
+// Canvas wraps a <canvas> element and its 2D rendering context.
+type Canvas struct {
+	Element
+	ctx js.Value
+}
+
+// NewCanvas returns a Canvas for the given element id, acquiring its "2d"
+// rendering context. Like GetElementByID, it never returns nil — if no
+// element with that id was found, the returned Canvas's Found() is false and
+// its drawing methods no-op.
+func NewCanvas(id string) *Canvas {
+	el := GetElementByID(id)
+	if !el.Found() {
+		return &Canvas{Element: *el}
+	}
+	return &Canvas{Element: *el, ctx: el.Value.Call("getContext", "2d")}
+}
+
+// FillRect fills a rectangle with the given color.
+func (c *Canvas) FillRect(x, y, w, h float64, color string) {
+	if !c.Found() {
+		return
+	}
+	c.ctx.Set("fillStyle", color)
+	c.ctx.Call("fillRect", x, y, w, h)
+}
+
+// ClearRect clears a rectangle of the canvas.
+func (c *Canvas) ClearRect(x, y, w, h float64) {
+	if !c.Found() {
+		return
+	}
+	c.ctx.Call("clearRect", x, y, w, h)
+}
+
+// StrokeText draws outlined text at the given position.
+func (c *Canvas) StrokeText(text string, x, y float64) {
+	if !c.Found() {
+		return
+	}
+	c.ctx.Call("strokeText", text, x, y)
+}
@@ -1,6 +1,28 @@
 package main
 
-import "syscall/js"
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"syscall/js"
+	"time"
+
+	"nrf5340-app/bridge"
+	"nrf5340-app/dom"
+	"nrf5340-app/webble"
+)
+
+// nordicUARTService is the Nordic UART Service GATT UUID, the common
+// transport for streaming data to/from an nRF5340 over BLE.
+const nordicUARTService = "6e400001-b5a3-f393-e0a9-e50e24dcca9e"
+
+var errMissingArgs = errors.New("missing required arguments")
+
+// telemetryBridge holds the currently open telemetry bridge, if any, so
+// closeTelemetryBridge can tear it down and openTelemetryBridge can replace
+// a stale one instead of leaking it.
+var telemetryBridge *bridge.Port
 
 // getNumber returns the number 99
 func getNumber() int {
@@ -17,24 +39,97 @@ func multiply(a, b int) int {
 	return a * b
 }
 
+// slowAdd simulates long-running Go work (crypto, parsing, BLE I/O) so it
+// can demonstrate jsPromise instead of blocking the caller.
+func slowAdd(ctx context.Context, a, b int) (interface{}, error) {
+	select {
+	case <-time.After(500 * time.Millisecond):
+		return add(a, b), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// mountDeviceStatusUI sets the text of a "#device-status" element, if present
+// on the page, to "disconnected" from Go. Nothing updates it afterwards;
+// pairing or connecting a device does not change it.
+func mountDeviceStatusUI() {
+	status := dom.GetElementByID("device-status")
+	if !status.Found() {
+		return
+	}
+	status.SetText("disconnected")
+}
+
+// echoTelemetry is a placeholder bridge consumer: it acknowledges every
+// inbound message so the channel has a working round trip to build on until
+// real firmware telemetry parsing lands here.
+func echoTelemetry(in <-chan bridge.Message, out chan<- bridge.Message) {
+	for msg := range in {
+		out <- bridge.Message{ID: msg.ID, Type: "ack", Payload: msg.Payload}
+	}
+}
+
 // registerFunctions registers the Go functions with the WASM environment
 func registerFunctions() {
-	js.Global().Set("getNumber", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		return getNumber()
+	Register("getNumber", getNumber)
+	Register("add", add)
+	Register("multiply", multiply)
+
+	mountDeviceStatusUI()
+
+	// pairNordicUART() prompts for a BLE device advertising the Nordic UART
+	// Service and resolves once its connection is established.
+	js.Global().Set("pairNordicUART", jsPromise(func(ctx context.Context, args []js.Value) (interface{}, error) {
+		device, err := webble.RequestDevice([]webble.ServiceFilter{{Services: []string{nordicUARTService}}})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := device.Connect(); err != nil {
+			return nil, err
+		}
+		return device.Value, nil
 	}))
-	
-	js.Global().Set("add", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		if len(args) >= 2 {
-			return add(args[0].Int(), args[1].Int())
+
+	// openTelemetryBridge() opens a duplex MessageChannel for streaming
+	// firmware telemetry and returns the MessagePort JS should transfer to
+	// a worker or hand to the page. closeTelemetryBridge() tears it down.
+	js.Global().Set("openTelemetryBridge", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if telemetryBridge != nil {
+			telemetryBridge.Close()
 		}
-		return 0
+
+		port, in, out, jsPort := bridge.Open()
+		telemetryBridge = port
+		go echoTelemetry(in, out)
+		return jsPort
 	}))
-	
-	js.Global().Set("multiply", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		if len(args) >= 2 {
-			return multiply(args[0].Int(), args[1].Int())
+
+	js.Global().Set("closeTelemetryBridge", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if telemetryBridge != nil {
+			telemetryBridge.Close()
+			telemetryBridge = nil
+		}
+		return nil
+	}))
+
+	// checksumFirmware(bytes) appends a CRC32 checksum to the firmware image
+	// so JS can verify the transfer was intact, exercising the typed-array
+	// binary registration path used for firmware image transfers.
+	RegisterBinary("checksumFirmware", func(b []byte) ([]byte, error) {
+		sum := crc32.ChecksumIEEE(b)
+		out := make([]byte, len(b)+4)
+		copy(out, b)
+		binary.BigEndian.PutUint32(out[len(b):], sum)
+		return out, nil
+	})
+
+	// slowAdd(a, b, signal?) returns a Promise so JS never blocks on it.
+	js.Global().Set("slowAdd", jsPromise(func(ctx context.Context, args []js.Value) (interface{}, error) {
+		if len(args) < 2 {
+			return nil, errMissingArgs
 		}
-		return 0
+		return slowAdd(ctx, args[0].Int(), args[1].Int())
 	}))
 }
 
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"syscall/js"
+)
+
+// jsPromise wraps a long-running Go function as a JS Promise, running fn on
+// its own goroutine so the JS event loop (and js.FuncOf callbacks) are never
+// blocked. fn receives the call's arguments verbatim, minus a trailing
+// AbortSignal if the caller passed one (e.g. `controller.signal`) — aborting
+// that signal (or it already being aborted at call time) cancels the context
+// passed to fn.
+func jsPromise(fn func(ctx context.Context, args []js.Value) (interface{}, error)) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var signal js.Value
+		var abortHandler js.Func
+		hasListener := false
+
+		if n := len(args); n > 0 && isAbortSignal(args[n-1]) {
+			signal = args[n-1]
+			args = args[:n-1]
+
+			if signal.Get("aborted").Bool() {
+				cancel()
+			} else {
+				abortHandler = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+					cancel()
+					return nil
+				})
+				signal.Call("addEventListener", "abort", abortHandler)
+				hasListener = true
+			}
+		}
+
+		handler := js.FuncOf(func(this js.Value, resolveReject []js.Value) interface{} {
+			resolve := resolveReject[0]
+			reject := resolveReject[1]
+
+			go func() {
+				defer cancel()
+				defer func() {
+					if hasListener {
+						signal.Call("removeEventListener", "abort", abortHandler)
+						abortHandler.Release()
+					}
+				}()
+
+				result, err := fn(ctx, args)
+				if err != nil {
+					reject.Invoke(js.Global().Get("Error").New(err.Error()))
+					return
+				}
+				resolve.Invoke(result)
+			}()
+
+			return nil
+		})
+
+		promise := js.Global().Get("Promise").New(handler)
+		handler.Release()
+		return promise
+	})
+}
+
+// isAbortSignal reports whether v looks like a DOM AbortSignal (i.e. has an
+// "aborted" boolean property), used to detect a trailing signal argument.
+func isAbortSignal(v js.Value) bool {
+	return v.Type() == js.TypeObject && v.Get("aborted").Type() == js.TypeBoolean
+}
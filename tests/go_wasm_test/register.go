@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"syscall/js"
+)
+
+var errorInterface = reflect.TypeOf((*error)(nil)).Elem()
+
+// Register exposes fn under name on js.Global(), generating the js.FuncOf
+// wrapper via reflection instead of hand-written argument parsing. fn may
+// take any number of int, float64, string, []byte or struct parameters
+// (structs are decoded from the JS value via JSON.stringify) and return
+// either a single value or a (T, error) pair. A wrong argument count or type
+// yields a JS Error instead of a panic.
+func Register(name string, fn interface{}) {
+	fnVal := reflect.ValueOf(fn)
+	fnType := fnVal.Type()
+	if fnType.Kind() != reflect.Func {
+		panic(fmt.Sprintf("Register(%q): fn must be a function, got %s", name, fnType.Kind()))
+	}
+
+	js.Global().Set(name, js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		in, err := marshalArgs(fnType, args)
+		if err != nil {
+			return jsError(err)
+		}
+
+		out := fnVal.Call(in)
+		result, err := unmarshalResults(fnType, out)
+		if err != nil {
+			return jsError(err)
+		}
+		return result
+	}))
+}
+
+// marshalArgs converts JS args into reflect.Values matching fnType's
+// parameters, based on each parameter's kind.
+func marshalArgs(fnType reflect.Type, args []js.Value) ([]reflect.Value, error) {
+	if fnType.NumIn() != len(args) {
+		return nil, fmt.Errorf("expected %d argument(s), got %d", fnType.NumIn(), len(args))
+	}
+
+	in := make([]reflect.Value, fnType.NumIn())
+	for i := 0; i < fnType.NumIn(); i++ {
+		paramType := fnType.In(i)
+		v, err := marshalArg(paramType, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i, err)
+		}
+		in[i] = v
+	}
+	return in, nil
+}
+
+// marshalArg converts a single js.Value to a reflect.Value of paramType.
+func marshalArg(paramType reflect.Type, arg js.Value) (reflect.Value, error) {
+	switch paramType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if arg.Type() != js.TypeNumber {
+			return reflect.Value{}, fmt.Errorf("expected number, got %s", arg.Type())
+		}
+		i := reflect.New(paramType).Elem()
+		i.SetInt(int64(arg.Int()))
+		return i, nil
+	case reflect.Float32, reflect.Float64:
+		if arg.Type() != js.TypeNumber {
+			return reflect.Value{}, fmt.Errorf("expected number, got %s", arg.Type())
+		}
+		f := reflect.New(paramType).Elem()
+		f.SetFloat(arg.Float())
+		return f, nil
+	case reflect.String:
+		if arg.Type() != js.TypeString {
+			return reflect.Value{}, fmt.Errorf("expected string, got %s", arg.Type())
+		}
+		return reflect.ValueOf(arg.String()).Convert(paramType), nil
+	case reflect.Slice:
+		if paramType.Elem().Kind() == reflect.Uint8 {
+			if !arg.InstanceOf(js.Global().Get("Uint8Array")) {
+				return reflect.Value{}, fmt.Errorf("expected Uint8Array, got %s", arg.Type())
+			}
+			return reflect.ValueOf(FromJSBytes(arg)).Convert(paramType), nil
+		}
+	case reflect.Struct, reflect.Ptr, reflect.Map:
+		out := reflect.New(paramType)
+		if err := json.Unmarshal([]byte(js.Global().Get("JSON").Call("stringify", arg).String()), out.Interface()); err != nil {
+			return reflect.Value{}, fmt.Errorf("decoding %s: %w", paramType, err)
+		}
+		return out.Elem(), nil
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported parameter type %s", paramType)
+}
+
+// unmarshalResults converts fn's return values to a single JS-friendly
+// value, treating a trailing error return as a special case: a non-nil
+// error short-circuits into the caller's error path instead of being
+// marshaled as a value.
+func unmarshalResults(fnType reflect.Type, out []reflect.Value) (interface{}, error) {
+	if fnType.NumOut() == 0 {
+		return nil, nil
+	}
+
+	lastIdx := fnType.NumOut() - 1
+	if fnType.Out(lastIdx) == errorInterface {
+		if errVal := out[lastIdx]; !errVal.IsNil() {
+			return nil, errVal.Interface().(error)
+		}
+		out = out[:lastIdx]
+	}
+
+	switch len(out) {
+	case 0:
+		return nil, nil
+	case 1:
+		return unmarshalValue(out[0])
+	default:
+		return nil, fmt.Errorf("functions with more than one non-error return value are not supported")
+	}
+}
+
+// unmarshalValue converts a single Go return value to something js.ValueOf
+// (or the caller) can hand back to JS.
+func unmarshalValue(v reflect.Value) (interface{}, error) {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return ToJSBytes(v.Bytes()), nil
+		}
+		fallthrough
+	case reflect.Struct, reflect.Ptr, reflect.Map:
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("encoding result: %w", err)
+		}
+		return js.Global().Get("JSON").Call("parse", string(data)), nil
+	}
+	return v.Interface(), nil
+}
+
+// jsError builds a JS Error object for returning from a js.FuncOf.
+func jsError(err error) js.Value {
+	return js.Global().Get("Error").New(err.Error())
+}
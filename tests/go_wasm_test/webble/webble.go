@@ -0,0 +1,179 @@
+// Package webble binds the browser's Web Bluetooth API (navigator.bluetooth)
+// via syscall/js, so the nRF5340 host side can pair with a device and pipe
+// GATT characteristic notifications (e.g. Nordic UART Service) into Go for
+// protocol handling instead of JS.
+package webble
+
+import (
+	"errors"
+	"syscall/js"
+)
+
+// ServiceFilter narrows RequestDevice to devices advertising a given GATT
+// service UUID, mirroring the JS requestDevice filter shape.
+type ServiceFilter struct {
+	Services []string
+}
+
+func (f ServiceFilter) toJS() js.Value {
+	services := make([]interface{}, len(f.Services))
+	for i, s := range f.Services {
+		services[i] = s
+	}
+	return js.ValueOf(map[string]interface{}{"services": services})
+}
+
+// Device wraps a BluetoothDevice.
+type Device struct {
+	Value js.Value
+}
+
+// RequestDevice prompts the user to select a nearby device advertising one
+// of filters' services and resolves to a Device once chosen.
+func RequestDevice(filters []ServiceFilter) (*Device, error) {
+	jsFilters := make([]interface{}, len(filters))
+	for i, f := range filters {
+		jsFilters[i] = f.toJS()
+	}
+
+	result, err := await(bluetooth().Call("requestDevice", js.ValueOf(map[string]interface{}{
+		"filters": jsFilters,
+	})))
+	if err != nil {
+		return nil, err
+	}
+	return &Device{Value: result}, nil
+}
+
+// Connect connects to the device's GATT server.
+func (d *Device) Connect() (*GATTServer, error) {
+	result, err := await(d.Value.Get("gatt").Call("connect"))
+	if err != nil {
+		return nil, err
+	}
+	return &GATTServer{Value: result}, nil
+}
+
+// GATTServer wraps a BluetoothRemoteGATTServer.
+type GATTServer struct {
+	Value js.Value
+}
+
+// GetPrimaryService looks up a primary GATT service by UUID.
+func (s *GATTServer) GetPrimaryService(uuid string) (*Service, error) {
+	result, err := await(s.Value.Call("getPrimaryService", uuid))
+	if err != nil {
+		return nil, err
+	}
+	return &Service{Value: result}, nil
+}
+
+// Service wraps a BluetoothRemoteGATTService.
+type Service struct {
+	Value js.Value
+}
+
+// GetCharacteristic looks up a characteristic by UUID within the service.
+func (s *Service) GetCharacteristic(uuid string) (*Characteristic, error) {
+	result, err := await(s.Value.Call("getCharacteristic", uuid))
+	if err != nil {
+		return nil, err
+	}
+	return &Characteristic{Value: result}, nil
+}
+
+// Characteristic wraps a BluetoothRemoteGATTCharacteristic.
+type Characteristic struct {
+	Value     js.Value
+	notifyFn  js.Func
+	listening bool
+}
+
+// ReadValue reads the characteristic's current value.
+func (c *Characteristic) ReadValue() ([]byte, error) {
+	result, err := await(c.Value.Call("readValue"))
+	if err != nil {
+		return nil, err
+	}
+	return dataViewToBytes(result), nil
+}
+
+// WriteValue writes b to the characteristic.
+func (c *Characteristic) WriteValue(b []byte) error {
+	arr := js.Global().Get("Uint8Array").New(len(b))
+	js.CopyBytesToJS(arr, b)
+	_, err := await(c.Value.Call("writeValue", arr))
+	return err
+}
+
+// StartNotifications subscribes to characteristicvaluechanged events and
+// invokes onValue with each notified value. Call StopNotifications to tear
+// down the subscription and release its js.Func.
+func (c *Characteristic) StartNotifications(onValue func([]byte)) error {
+	if c.listening {
+		return errors.New("webble: notifications already started")
+	}
+
+	if _, err := await(c.Value.Call("startNotifications")); err != nil {
+		return err
+	}
+
+	c.notifyFn = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		value := args[0].Get("target").Get("value")
+		onValue(dataViewToBytes(value))
+		return nil
+	})
+	c.Value.Call("addEventListener", "characteristicvaluechanged", c.notifyFn)
+	c.listening = true
+	return nil
+}
+
+// StopNotifications unsubscribes and releases the notification callback.
+func (c *Characteristic) StopNotifications() {
+	if !c.listening {
+		return
+	}
+	c.Value.Call("removeEventListener", "characteristicvaluechanged", c.notifyFn)
+	c.notifyFn.Release()
+	c.listening = false
+}
+
+// bluetooth returns navigator.bluetooth.
+func bluetooth() js.Value {
+	return js.Global().Get("navigator").Get("bluetooth")
+}
+
+// dataViewToBytes copies the bytes backing a JS DataView into a Go slice.
+func dataViewToBytes(dv js.Value) []byte {
+	buf := js.Global().Get("Uint8Array").New(dv.Get("buffer"), dv.Get("byteOffset"), dv.Get("byteLength"))
+	b := make([]byte, buf.Get("length").Int())
+	js.CopyBytesToGo(b, buf)
+	return b
+}
+
+// await blocks the calling goroutine until the JS Promise p settles,
+// returning its resolved value or an error built from the rejection reason.
+// It must be called off the JS event loop goroutine (e.g. from within a
+// jsPromise-wrapped call), since it blocks on a channel.
+func await(p js.Value) (js.Value, error) {
+	done := make(chan struct{})
+	var result js.Value
+	var rejectErr error
+
+	thenFn := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		result = args[0]
+		close(done)
+		return nil
+	})
+	catchFn := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		rejectErr = errors.New(args[0].Call("toString").String())
+		close(done)
+		return nil
+	})
+	defer thenFn.Release()
+	defer catchFn.Release()
+
+	p.Call("then", thenFn).Call("catch", catchFn)
+	<-done
+	return result, rejectErr
+}